@@ -0,0 +1,309 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"mime"
+	"net"
+	"net/http"
+
+	"github.com/kalugin1988/hexnet_service/pkg/hexnet"
+	"gopkg.in/yaml.v3"
+)
+
+// apiError is one structured error in a response's errors[] array. Index is
+// set when the error is tied to a specific input route; it is omitted for
+// whole-request errors (bad JSON, bad hex, ...).
+type apiError struct {
+	Index   *int   `json:"index,omitempty"`
+	Message string `json:"message"`
+}
+
+type routeJSON struct {
+	Target  string `json:"target"`
+	NextHop string `json:"nexthop"`
+}
+
+type convertRequest struct {
+	Format string `json:"format"`
+	Input  string `json:"input"`
+}
+
+type convertResponse struct {
+	Routes     []routeJSON       `json:"routes,omitempty"`
+	Renderings hexnet.Renderings `json:"renderings,omitempty"`
+	Errors     []apiError        `json:"errors,omitempty"`
+}
+
+type encodeRequest struct {
+	Routes []routeJSON `json:"routes"`
+}
+
+type encodeResponse struct {
+	Hex    string     `json:"hex,omitempty"`
+	Errors []apiError `json:"errors,omitempty"`
+}
+
+type decodeRequest struct {
+	Hex string `json:"hex"`
+}
+
+type decodeResponse struct {
+	Routes []routeJSON `json:"routes,omitempty"`
+	Errors []apiError  `json:"errors,omitempty"`
+}
+
+var apiHTMLTmpl = template.Must(template.New("api").Parse(`
+<!DOCTYPE html>
+<html>
+<head><meta charset="UTF-8"><title>HexNet API</title>
+<style>body { font-family: sans-serif; margin: 20px; } pre { background: #f7f7f7; padding: 10px; white-space: pre-wrap; }</style>
+</head>
+<body><pre>{{.}}</pre></body>
+</html>
+`))
+
+// negotiateContentType picks a response representation from the Accept
+// header: application/yaml and text/html are recognized explicitly,
+// anything else (including no Accept header, or application/json) gets
+// plain JSON.
+func negotiateContentType(r *http.Request) string {
+	for _, accepted := range splitAccept(r.Header.Get("Accept")) {
+		switch accepted {
+		case "application/yaml", "application/x-yaml":
+			return "application/yaml"
+		case "text/html":
+			return "text/html"
+		case "application/json", "*/*":
+			return "application/json"
+		}
+	}
+	return "application/json"
+}
+
+func splitAccept(header string) []string {
+	var out []string
+	for _, part := range splitComma(header) {
+		if mt, _, err := mime.ParseMediaType(part); err == nil {
+			out = append(out, mt)
+		}
+	}
+	return out
+}
+
+func splitComma(s string) []string {
+	var out []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == ',' {
+			out = append(out, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(out, s[start:])
+}
+
+// writeNegotiated renders payload as JSON, YAML or an HTML page wrapping the
+// JSON, depending on the request's Accept header, and sets status.
+func writeNegotiated(w http.ResponseWriter, r *http.Request, status int, payload interface{}) {
+	ct := negotiateContentType(r)
+	w.Header().Set("Content-Type", ct)
+	w.WriteHeader(status)
+	switch ct {
+	case "application/yaml":
+		enc := yaml.NewEncoder(w)
+		defer enc.Close()
+		enc.Encode(payload)
+	case "text/html":
+		b, _ := json.MarshalIndent(payload, "", "  ")
+		apiHTMLTmpl.Execute(w, string(b))
+	default:
+		json.NewEncoder(w).Encode(payload)
+	}
+}
+
+// decodeBody reads a JSON or YAML request body into v, based on the
+// request's Content-Type header (defaulting to JSON when absent). It
+// returns a 415-worthy error for any other content type.
+func decodeBody(r *http.Request, v interface{}) error {
+	ct := r.Header.Get("Content-Type")
+	if ct == "" {
+		return json.NewDecoder(r.Body).Decode(v)
+	}
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return unsupportedContentTypeError{ct}
+	}
+	switch mt {
+	case "application/json":
+		return json.NewDecoder(r.Body).Decode(v)
+	case "application/yaml", "application/x-yaml":
+		return yaml.NewDecoder(r.Body).Decode(v)
+	default:
+		return unsupportedContentTypeError{mt}
+	}
+}
+
+type unsupportedContentTypeError struct{ contentType string }
+
+func (e unsupportedContentTypeError) Error() string {
+	return fmt.Sprintf("unsupported content type: %s", e.contentType)
+}
+
+func errorsResponse(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if _, ok := err.(unsupportedContentTypeError); ok {
+		status = http.StatusUnsupportedMediaType
+	}
+	writeNegotiated(w, r, status, struct {
+		Errors []apiError `json:"errors"`
+	}{Errors: []apiError{{Message: err.Error()}}})
+}
+
+func routesToJSON(routes []hexnet.Route) []routeJSON {
+	out := make([]routeJSON, 0, len(routes))
+	for _, rt := range routes {
+		ones, _ := rt.Target.Mask.Size()
+		out = append(out, routeJSON{
+			Target:  fmt.Sprintf("%s/%d", rt.Target.IP.String(), ones),
+			NextHop: rt.NextHop.String(),
+		})
+	}
+	return out
+}
+
+// apiConvertHandler implements POST /api/convert: it parses input in any of
+// the formats supported by the web UI and returns the parsed routes plus
+// every renderer's output, for use from provisioning scripts.
+func apiConvertHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req convertRequest
+	if err := decodeBody(r, &req); err != nil {
+		errorsResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	format := req.Format
+	if format == "" || format == hexnet.FormatAuto {
+		format = hexnet.DetectFormat(req.Input)
+	}
+
+	routes, err := hexnet.ParseInput(format, req.Input)
+	if err != nil {
+		recordParseError(err)
+		errorsResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if format != hexnet.FormatCIDR {
+		decodeTotal.Inc()
+	}
+
+	rend, err := hexnet.RenderAll(routes)
+	if err != nil {
+		recordParseError(err)
+		errorsResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+	encodeTotal.Inc()
+
+	writeNegotiated(w, r, http.StatusOK, convertResponse{Routes: routesToJSON(routes), Renderings: rend})
+}
+
+// apiEncodeHandler implements POST /api/encode: it turns a list of
+// target/nexthop routes into the "0x..." RFC 3442 hex form. Each route that
+// fails to parse is reported as its own entry in errors[], indexed into the
+// request's routes array, rather than aborting the whole request.
+func apiEncodeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req encodeRequest
+	if err := decodeBody(r, &req); err != nil {
+		errorsResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	var resp encodeResponse
+	var routes []hexnet.Route
+	for i, rj := range req.Routes {
+		_, ipNet, err := net.ParseCIDR(rj.Target)
+		if err != nil {
+			idx := i
+			msg := fmt.Sprintf("invalid target CIDR: %v", err)
+			recordParseError(fmt.Errorf("%s", msg))
+			resp.Errors = append(resp.Errors, apiError{Index: &idx, Message: msg})
+			continue
+		}
+		nh := net.ParseIP(rj.NextHop)
+		if nh == nil {
+			idx := i
+			msg := fmt.Sprintf("invalid route IP: %s", rj.NextHop)
+			recordParseError(fmt.Errorf("%s", msg))
+			resp.Errors = append(resp.Errors, apiError{Index: &idx, Message: msg})
+			continue
+		}
+		routes = append(routes, hexnet.Route{Target: ipNet, NextHop: nh})
+	}
+
+	if len(routes) > 0 {
+		data, err := hexnet.Encode(routes)
+		if err != nil {
+			recordParseError(err)
+			resp.Errors = append(resp.Errors, apiError{Message: err.Error()})
+		} else {
+			encodeTotal.Inc()
+			resp.Hex = "0x" + hex.EncodeToString(data)
+		}
+	}
+
+	status := http.StatusOK
+	if len(resp.Errors) > 0 && resp.Hex == "" {
+		status = http.StatusBadRequest
+	}
+	writeNegotiated(w, r, status, resp)
+}
+
+// apiDecodeHandler implements POST /api/decode: it turns a "0x..." RFC 3442
+// hex stream back into its routes.
+func apiDecodeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req decodeRequest
+	if err := decodeBody(r, &req); err != nil {
+		errorsResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	pairs, err := hexnet.ParseHexStream(req.Hex)
+	if err != nil && len(pairs) == 0 {
+		recordParseError(err)
+		errorsResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+	decodeTotal.Inc()
+
+	var resp decodeResponse
+	for i, p := range pairs {
+		if p.Error != "" {
+			idx := i
+			resp.Errors = append(resp.Errors, apiError{Index: &idx, Message: p.Error})
+			continue
+		}
+		resp.Routes = append(resp.Routes, routeJSON{Target: p.Target, NextHop: p.Route})
+	}
+	if err != nil {
+		resp.Errors = append(resp.Errors, apiError{Message: err.Error()})
+	}
+
+	writeNegotiated(w, r, http.StatusOK, resp)
+}
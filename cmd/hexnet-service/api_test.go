@@ -0,0 +1,192 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gopkg.in/yaml.v3"
+)
+
+func TestApiEncodeHandlerJSON(t *testing.T) {
+	body := `{"routes":[{"target":"192.168.1.0/24","nexthop":"192.168.0.1"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/encode", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	apiEncodeHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp encodeResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if resp.Hex != "0x18c0a801c0a80001" {
+		t.Errorf("Hex = %q, want %q", resp.Hex, "0x18c0a801c0a80001")
+	}
+	if len(resp.Errors) != 0 {
+		t.Errorf("Errors = %v, want none", resp.Errors)
+	}
+}
+
+func TestApiEncodeHandlerPartialErrorsStillReturn200(t *testing.T) {
+	body := `{"routes":[{"target":"192.168.1.0/24","nexthop":"192.168.0.1"},{"target":"not-a-cidr","nexthop":"192.168.0.1"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/encode", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	apiEncodeHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (at least one route encoded); body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp encodeResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if resp.Hex == "" {
+		t.Error("Hex should still be populated from the one valid route")
+	}
+	if len(resp.Errors) != 1 {
+		t.Fatalf("Errors = %v, want exactly one entry", resp.Errors)
+	}
+	if resp.Errors[0].Index == nil || *resp.Errors[0].Index != 1 {
+		t.Errorf("Errors[0].Index = %v, want pointer to 1", resp.Errors[0].Index)
+	}
+}
+
+func TestApiEncodeHandlerAllInvalidReturns400(t *testing.T) {
+	body := `{"routes":[{"target":"not-a-cidr","nexthop":"192.168.0.1"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/encode", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	apiEncodeHandler(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d; body = %s", w.Code, http.StatusBadRequest, w.Body.String())
+	}
+}
+
+func TestApiEncodeHandlerNegotiatesYAML(t *testing.T) {
+	body := `{"routes":[{"target":"192.168.1.0/24","nexthop":"192.168.0.1"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/encode", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/yaml")
+	w := httptest.NewRecorder()
+
+	apiEncodeHandler(w, req)
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/yaml" {
+		t.Fatalf("Content-Type = %q, want application/yaml", ct)
+	}
+	var resp encodeResponse
+	if err := yaml.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding YAML response: %v (body = %s)", err, w.Body.String())
+	}
+	if resp.Hex == "" {
+		t.Error("Hex should be populated")
+	}
+}
+
+func TestApiEncodeHandlerRejectsUnsupportedContentType(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/encode", strings.NewReader("routes=1"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+
+	apiEncodeHandler(w, req)
+
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnsupportedMediaType)
+	}
+}
+
+func TestApiDecodeHandlerRoundTrip(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/decode", strings.NewReader(`{"hex":"0x18c0a801c0a80001"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	apiDecodeHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp decodeResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(resp.Routes) != 1 || resp.Routes[0].Target != "192.168.1.0/24" {
+		t.Errorf("Routes = %+v, want one route 192.168.1.0/24", resp.Routes)
+	}
+}
+
+func TestApiDecodeHandlerRejectsBadHex(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/decode", strings.NewReader(`{"hex":"0xzz"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	apiDecodeHandler(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d; body = %s", w.Code, http.StatusBadRequest, w.Body.String())
+	}
+}
+
+func TestApiConvertHandlerCountsDecodeForAutoDetectedFormat(t *testing.T) {
+	before := testutil.ToFloat64(decodeTotal)
+
+	body := `{"input":"--dhcp-option=121,192.168.1.0/24,192.168.0.1"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/convert", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	apiConvertHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if got := testutil.ToFloat64(decodeTotal); got != before+1 {
+		t.Errorf("decodeTotal = %v, want %v (auto-detected dnsmasq format should count as a decode)", got, before+1)
+	}
+}
+
+func TestApiConvertHandlerCountsDecodeForExplicitNonHexFormat(t *testing.T) {
+	before := testutil.ToFloat64(decodeTotal)
+
+	body := `{"format":"dnsmasq","input":"--dhcp-option=121,192.168.1.0/24,192.168.0.1"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/convert", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	apiConvertHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if got := testutil.ToFloat64(decodeTotal); got != before+1 {
+		t.Errorf("decodeTotal = %v, want %v (explicit dnsmasq format should count as a decode)", got, before+1)
+	}
+}
+
+func TestApiConvertHandlerDoesNotCountDecodeForCIDRFormat(t *testing.T) {
+	before := testutil.ToFloat64(decodeTotal)
+
+	body := `{"format":"cidr","input":"192.168.1.0/24 192.168.0.1"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/convert", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	apiConvertHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if got := testutil.ToFloat64(decodeTotal); got != before {
+		t.Errorf("decodeTotal = %v, want unchanged at %v (cidr input is an encode, not a decode)", got, before)
+	}
+}
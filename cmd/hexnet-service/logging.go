@@ -0,0 +1,74 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// statusRecorder captures the status code and byte count a handler writes,
+// so the logging middleware can report them after the fact.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	s.status = code
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	n, err := s.ResponseWriter.Write(b)
+	s.bytes += n
+	return n, err
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// loggingMiddleware wraps next with structured request logging and latency
+// metrics: one slog line per request (method, path, status, bytes,
+// request ID) plus an observation on hexnet_http_request_duration_seconds.
+// It also assigns a request ID when the client didn't send one, echoing it
+// back via the X-Request-Id response header.
+//
+// route is the registered mux pattern (e.g. "/sets/:name"), not the actual
+// request path, and is used only for the metric label: /sets/{name} and
+// friends embed an arbitrary, user-chosen route-set name, and labeling the
+// metric with the raw path would mint a new, never-cleaned-up label series
+// per distinct name. The full path is still logged, just not used as a
+// label.
+func loggingMiddleware(route string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqID := r.Header.Get("X-Request-Id")
+		if reqID == "" {
+			reqID = newRequestID()
+		}
+		w.Header().Set("X-Request-Id", reqID)
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(rec, r)
+		duration := time.Since(start)
+
+		requestDuration.WithLabelValues(r.Method, route, strconv.Itoa(rec.status)).Observe(duration.Seconds())
+		slog.Info("http request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"bytes", rec.bytes,
+			"request_id", reqID,
+			"duration_ms", duration.Milliseconds(),
+		)
+	})
+}
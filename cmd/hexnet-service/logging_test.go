@@ -0,0 +1,54 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLoggingMiddlewareAssignsRequestID(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte("hi"))
+	})
+	handler := loggingMiddleware("/test", next)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusTeapot {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusTeapot)
+	}
+	if w.Header().Get("X-Request-Id") == "" {
+		t.Error("expected X-Request-Id to be set when the client didn't send one")
+	}
+}
+
+func TestLoggingMiddlewareEchoesClientRequestID(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := loggingMiddleware("/test", next)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("X-Request-Id", "client-supplied-id")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if got := w.Header().Get("X-Request-Id"); got != "client-supplied-id" {
+		t.Errorf("X-Request-Id = %q, want it echoed back unchanged", got)
+	}
+}
+
+func TestStatusRecorderDefaultsToOKWhenWriteHeaderNotCalled(t *testing.T) {
+	rec := &statusRecorder{ResponseWriter: httptest.NewRecorder(), status: http.StatusOK}
+	n, err := rec.Write([]byte("hello"))
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if n != 5 || rec.bytes != 5 {
+		t.Errorf("bytes written = %d (recorded %d), want 5", n, rec.bytes)
+	}
+	if rec.status != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.status, http.StatusOK)
+	}
+}
@@ -0,0 +1,216 @@
+// Command hexnet-service runs the HexNet HTTP service: a web page plus a
+// JSON API for converting DHCP classless static route (option 121/249)
+// entries between CIDR/hex text and the textual forms used by ISC dhcpd,
+// dnsmasq and MikroTik RouterOS. The conversion logic itself lives in
+// pkg/hexnet so other tools can import it without spawning this server.
+package main
+
+import (
+	"html/template"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/kalugin1988/hexnet_service/pkg/hexnet"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var tmpl = template.Must(template.New("page").Parse(`
+<!DOCTYPE html>
+<html>
+<head>
+<meta charset="UTF-8">
+<title>HexNet конвертер</title>
+<style>
+ body { font-family: sans-serif; margin: 20px; }
+ textarea { width: 100%; height: 150px; }
+ table { border-collapse: collapse; margin-top: 20px; }
+ th, td { border: 1px solid #ccc; padding: 6px 12px; }
+ th { background: #eee; }
+ pre { background: #f7f7f7; padding: 10px; white-space: pre-wrap; }
+</style>
+<script>
+function copyAs(which) {
+	var el = document.getElementById("render-" + which);
+	if (!el) { return; }
+	navigator.clipboard.writeText(el.textContent);
+}
+</script>
+</head>
+<body>
+<h1>HexNet конвертер для DHCP MikroTik 121 и 249</h1>
+<p>Введите по строкам targetCIDR routeIP (поддерживаются и IPv4, и IPv6, например
+"2001:db8::/32 fe80::1"), hex-поток (0x...), ISC dhcpd
+"option classless-static-routes = ...;", dnsmasq "--dhcp-option=121,..." или
+скрипт MikroTik "/ip dhcp-server option add ... value=0x..." — формат
+определяется автоматически. Потоки со смешанными IPv4/IPv6-записями кодируются
+с маркером в начале hex-строки.</p>
+<form method="POST">
+<textarea name="data" placeholder="192.168.0.0/24 192.168.0.1"></textarea><br>
+<input type="submit" value="Convert">
+</form>
+{{if .Sets}}
+<h2>Сохранённые route sets</h2>
+<table>
+<tr><th>Name</th><th>Updated</th><th></th></tr>
+{{range .Sets}}
+<tr>
+ <td>{{.Name}}</td>
+ <td>{{.UpdatedAt}}</td>
+ <td><a href="/sets/{{.Name}}">JSON</a> · <a href="/sets/{{.Name}}.hex">.hex</a> · <a href="/sets/{{.Name}}/history">history</a></td>
+</tr>
+{{end}}
+</table>
+{{end}}
+{{if .Results}}
+<table>
+<tr><th>Target</th><th>Route</th><th>0хPrefixTragetRoute</th><th>Error</th></tr>
+{{range .Results}}
+<tr>
+ <td>{{.Target}}</td>
+ <td>{{.Route}}</td>
+ <td>{{.Hex}}</td>
+ <td style="color:red">{{.Error}}</td>
+</tr>
+{{end}}
+</table>
+{{end}}
+{{if .Renderings}}
+<h2>Copy as</h2>
+<p>
+<button type="button" onclick="copyAs('hex')">Copy hex</button>
+<button type="button" onclick="copyAs('dhcpd')">Copy ISC dhcpd</button>
+<button type="button" onclick="copyAs('dnsmasq')">Copy dnsmasq</button>
+<button type="button" onclick="copyAs('mikrotik')">Copy MikroTik</button>
+</p>
+<pre id="render-hex">{{.Renderings.Hex}}</pre>
+<pre id="render-dhcpd">{{.Renderings.Dhcpd}}</pre>
+<pre id="render-dnsmasq">{{.Renderings.Dnsmasq}}</pre>
+<pre id="render-mikrotik">{{.Renderings.MikroTik}}</pre>
+{{end}}
+</body>
+</html>
+`))
+
+type pageData struct {
+	Results    []hexnet.Pair
+	Renderings *hexnet.Renderings
+	Sets       []routeSet
+}
+
+// pageHandler serves the HTML UI. It reads store only to list existing
+// route sets on the index page; saving/editing a set goes through the
+// /sets/{name} JSON API instead of this form.
+func pageHandler(store *Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			sets, err := store.List()
+			if err != nil {
+				slog.Error("listing route sets", "error", err)
+			}
+			tmpl.Execute(w, pageData{Sets: sets})
+			return
+		}
+		if r.Method == http.MethodPost {
+			data := r.FormValue("data")
+			lines := strings.Split(strings.TrimSpace(data), "\n")
+
+			// The hex/CIDR "one entry per line" form keeps its historical
+			// line-by-line error reporting; the other pasted formats are
+			// parsed as a whole document.
+			format := hexnet.DetectFormat(data)
+			var results []hexnet.Pair
+			var parsedRoutes []hexnet.Route
+			if format == hexnet.FormatHex || format == hexnet.FormatCIDR {
+				for _, line := range lines {
+					line = strings.TrimSpace(line)
+					if line == "" {
+						continue
+					}
+					parts := strings.Fields(line)
+					if len(parts) == 1 { // hex stream
+						h := parts[0]
+						recs, err := hexnet.ParseHexStream(h)
+						if err != nil {
+							recordParseError(err)
+							results = append(results, hexnet.Pair{Hex: h, Error: err.Error()})
+						} else {
+							decodeTotal.Inc()
+							results = append(results, recs...)
+						}
+					} else if len(parts) == 2 {
+						t, rIP := parts[0], parts[1]
+						res, err := hexnet.BuildHexString(t, rIP)
+						p := hexnet.Pair{Target: t, Route: rIP}
+						if err != nil {
+							recordParseError(err)
+							p.Error = err.Error()
+						} else {
+							encodeTotal.Inc()
+							p.Hex = res
+						}
+						results = append(results, p)
+					} else {
+						results = append(results, hexnet.Pair{Error: "line format invalid: " + line})
+					}
+				}
+				if routes, err := hexnet.ParseInput(format, data); err == nil {
+					parsedRoutes = routes
+				}
+			} else {
+				routes, err := hexnet.ParseInput(format, data)
+				if err != nil {
+					recordParseError(err)
+					results = append(results, hexnet.Pair{Error: err.Error()})
+				} else {
+					decodeTotal.Inc()
+					results = hexnet.RoutesToPairs(routes)
+					parsedRoutes = routes
+				}
+			}
+
+			pd := pageData{Results: results}
+			if len(parsedRoutes) > 0 {
+				if rend, err := hexnet.RenderAll(parsedRoutes); err == nil {
+					encodeTotal.Inc()
+					pd.Renderings = &rend
+				}
+			}
+			tmpl.Execute(w, pd)
+			return
+		}
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// dbPath is where the route-set store lives. The service is a single
+// small binary with no config file yet, so this is hardcoded like the
+// listen address below.
+const dbPath = "hexnet_sets.db"
+
+func main() {
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	store, err := OpenStore(dbPath)
+	if err != nil {
+		slog.Error("opening route set store", "error", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	mux := http.NewServeMux()
+	mux.Handle("/", loggingMiddleware("/", pageHandler(store)))
+	mux.Handle("/api/convert", loggingMiddleware("/api/convert", http.HandlerFunc(apiConvertHandler)))
+	mux.Handle("/api/encode", loggingMiddleware("/api/encode", http.HandlerFunc(apiEncodeHandler)))
+	mux.Handle("/api/decode", loggingMiddleware("/api/decode", http.HandlerFunc(apiDecodeHandler)))
+	mux.Handle("/sets/", loggingMiddleware("/sets/:name", setsHandler(store)))
+	mux.Handle("/metrics", promhttp.Handler())
+
+	addr := ":8080"
+	slog.Info("hexnet service with UI listening", "addr", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		slog.Error("server failed", "error", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	encodeTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "hexnet_encode_total",
+		Help: "Total number of routes-to-hex encode operations.",
+	})
+	decodeTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "hexnet_decode_total",
+		Help: "Total number of hex-to-routes decode operations.",
+	})
+	parseErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "hexnet_parse_errors_total",
+		Help: "Total number of input parse errors, by stage.",
+	}, []string{"stage"})
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "hexnet_http_request_duration_seconds",
+		Help:    "HTTP request latency in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "path", "status"})
+)
+
+// classifyParseStage maps a parse error to a coarse stage label for
+// hexnet_parse_errors_total. pkg/hexnet's errors use a handful of
+// consistent message prefixes, which is what we key off of here rather than
+// introducing typed errors just for metrics.
+func classifyParseStage(err error) string {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "hex string"):
+		return "hex"
+	case strings.Contains(msg, "CIDR"):
+		return "cidr"
+	case strings.Contains(msg, "route IP"), strings.Contains(msg, "next hop"), strings.Contains(msg, "nexthop"):
+		return "route"
+	default:
+		return "other"
+	}
+}
+
+func recordParseError(err error) {
+	if err == nil {
+		return
+	}
+	parseErrorsTotal.WithLabelValues(classifyParseStage(err)).Inc()
+}
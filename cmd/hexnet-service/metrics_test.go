@@ -0,0 +1,28 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClassifyParseStage(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{errors.New("invalid hex string: encoding/hex: odd length hex string"), "hex"},
+		{errors.New("invalid target CIDR: invalid CIDR address"), "cidr"},
+		{errors.New("invalid route IP: nope"), "route"},
+		{errors.New("something else entirely"), "other"},
+	}
+	for _, tt := range tests {
+		if got := classifyParseStage(tt.err); got != tt.want {
+			t.Errorf("classifyParseStage(%q) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRecordParseErrorIgnoresNil(t *testing.T) {
+	// Must not panic or touch the counter vec with an empty label.
+	recordParseError(nil)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kalugin1988/hexnet_service/pkg/hexnet"
+)
+
+// setsHandler dispatches /sets/{name}, /sets/{name}/history and
+// /sets/{name}.hex. The store has no nested router, so the sub-path is
+// parsed by hand, same as the rest of this small service.
+func setsHandler(store *Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rest := strings.TrimPrefix(r.URL.Path, "/sets/")
+		if rest == "" {
+			listSetsHandler(store, w, r)
+			return
+		}
+
+		if name, ok := strings.CutSuffix(rest, "/history"); ok {
+			historyHandler(store, w, r, name)
+			return
+		}
+		if name, ok := strings.CutSuffix(rest, ".hex"); ok {
+			hexFileHandler(store, w, r, name)
+			return
+		}
+
+		name := rest
+		switch r.Method {
+		case http.MethodGet:
+			getSetHandler(store, w, r, name)
+		case http.MethodPost:
+			putSetHandler(store, w, r, name)
+		case http.MethodDelete:
+			deleteSetHandler(store, w, r, name)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+// listSetsHandler implements GET /sets: every saved route set, for
+// provisioning scripts that want to discover what's available.
+func listSetsHandler(store *Store, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	sets, err := store.List()
+	if err != nil {
+		errorsResponse(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeNegotiated(w, r, http.StatusOK, struct {
+		Sets []routeSet `json:"sets"`
+	}{Sets: sets})
+}
+
+// getSetHandler implements GET /sets/{name}.
+func getSetHandler(store *Store, w http.ResponseWriter, r *http.Request, name string) {
+	rs, ok, err := store.Get(name)
+	if err != nil {
+		errorsResponse(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	if !ok {
+		errorsResponse(w, r, http.StatusNotFound, fmt.Errorf("no route set named %q", name))
+		return
+	}
+	writeNegotiated(w, r, http.StatusOK, rs)
+}
+
+// putSetHandler implements POST /sets/{name}: create or overwrite the named
+// route set with the routes in the request body.
+func putSetHandler(store *Store, w http.ResponseWriter, r *http.Request, name string) {
+	var req encodeRequest
+	if err := decodeBody(r, &req); err != nil {
+		errorsResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	rs, err := store.Save(name, req.Routes)
+	if err != nil {
+		errorsResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+	writeNegotiated(w, r, http.StatusOK, rs)
+}
+
+// deleteSetHandler implements DELETE /sets/{name}.
+func deleteSetHandler(store *Store, w http.ResponseWriter, r *http.Request, name string) {
+	if _, ok, err := store.Get(name); err != nil {
+		errorsResponse(w, r, http.StatusInternalServerError, err)
+		return
+	} else if !ok {
+		errorsResponse(w, r, http.StatusNotFound, fmt.Errorf("no route set named %q", name))
+		return
+	}
+	if err := store.Delete(name); err != nil {
+		errorsResponse(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// historyHandler implements GET /sets/{name}/history.
+func historyHandler(store *Store, w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if _, ok, err := store.Get(name); err != nil {
+		errorsResponse(w, r, http.StatusInternalServerError, err)
+		return
+	} else if !ok {
+		errorsResponse(w, r, http.StatusNotFound, fmt.Errorf("no route set named %q", name))
+		return
+	}
+	history, err := store.History(name)
+	if err != nil {
+		errorsResponse(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeNegotiated(w, r, http.StatusOK, struct {
+		Name    string         `json:"name"`
+		History []historyEntry `json:"history"`
+	}{Name: name, History: history})
+}
+
+// hexFileHandler implements GET /sets/{name}.hex: the set's current hex
+// rendering as plain text, meant to be fetched directly by MikroTik
+// provisioning scripts.
+func hexFileHandler(store *Store, w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	rs, ok, err := store.Get(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		http.Error(w, fmt.Sprintf("no route set named %q", name), http.StatusNotFound)
+		return
+	}
+	routes, err := routeJSONsToRoutes(rs.Routes)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rend, err := hexnet.RenderAll(routes)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, rend.Hex)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSetsHandlerPutGetDeleteRoundTrip(t *testing.T) {
+	store := openTestStore(t)
+	handler := setsHandler(store)
+
+	put := httptest.NewRequest(http.MethodPost, "/sets/office", strings.NewReader(
+		`{"routes":[{"target":"192.168.1.0/24","nexthop":"192.168.0.1"}]}`))
+	put.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler(w, put)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d, want %d; body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	get := httptest.NewRequest(http.MethodGet, "/sets/office", nil)
+	w = httptest.NewRecorder()
+	handler(w, get)
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var rs routeSet
+	if err := json.Unmarshal(w.Body.Bytes(), &rs); err != nil {
+		t.Fatalf("decoding GET response: %v", err)
+	}
+	if rs.Name != "office" || len(rs.Routes) != 1 {
+		t.Errorf("GET returned %+v, want one route named office", rs)
+	}
+
+	del := httptest.NewRequest(http.MethodDelete, "/sets/office", nil)
+	w = httptest.NewRecorder()
+	handler(w, del)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("DELETE status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+
+	get = httptest.NewRequest(http.MethodGet, "/sets/office", nil)
+	w = httptest.NewRecorder()
+	handler(w, get)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("GET after DELETE status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestSetsHandlerGetMissingReturns404(t *testing.T) {
+	store := openTestStore(t)
+	handler := setsHandler(store)
+
+	req := httptest.NewRequest(http.MethodGet, "/sets/nope", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestSetsHandlerHistory(t *testing.T) {
+	store := openTestStore(t)
+	handler := setsHandler(store)
+
+	put := httptest.NewRequest(http.MethodPost, "/sets/office", strings.NewReader(
+		`{"routes":[{"target":"192.168.1.0/24","nexthop":"192.168.0.1"}]}`))
+	put.Header.Set("Content-Type", "application/json")
+	handler(httptest.NewRecorder(), put)
+
+	req := httptest.NewRequest(http.MethodGet, "/sets/office/history", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp struct {
+		Name    string         `json:"name"`
+		History []historyEntry `json:"history"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(resp.History) != 1 {
+		t.Fatalf("History = %v, want one entry", resp.History)
+	}
+}
+
+func TestSetsHandlerHexFile(t *testing.T) {
+	store := openTestStore(t)
+	handler := setsHandler(store)
+
+	put := httptest.NewRequest(http.MethodPost, "/sets/office", strings.NewReader(
+		`{"routes":[{"target":"192.168.1.0/24","nexthop":"192.168.0.1"}]}`))
+	put.Header.Set("Content-Type", "application/json")
+	handler(httptest.NewRecorder(), put)
+
+	req := httptest.NewRequest(http.MethodGet, "/sets/office.hex", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if got := strings.TrimSpace(w.Body.String()); got != "0x18c0a801c0a80001" {
+		t.Errorf(".hex body = %q, want %q", got, "0x18c0a801c0a80001")
+	}
+}
+
+func TestSetsHandlerList(t *testing.T) {
+	store := openTestStore(t)
+	handler := setsHandler(store)
+
+	put := httptest.NewRequest(http.MethodPost, "/sets/office", strings.NewReader(
+		`{"routes":[{"target":"192.168.1.0/24","nexthop":"192.168.0.1"}]}`))
+	put.Header.Set("Content-Type", "application/json")
+	handler(httptest.NewRecorder(), put)
+
+	req := httptest.NewRequest(http.MethodGet, "/sets/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var resp struct {
+		Sets []routeSet `json:"sets"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(resp.Sets) != 1 {
+		t.Fatalf("Sets = %v, want one entry", resp.Sets)
+	}
+}
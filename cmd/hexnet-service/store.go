@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/kalugin1988/hexnet_service/pkg/hexnet"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Store persists named route sets in a bbolt file so ops teams can point
+// provisioning scripts at a stable URL (GET /sets/{name}.hex) instead of
+// re-pasting routes on every run. Every save also appends a timestamped
+// snapshot to that set's history bucket for change-tracking.
+type Store struct {
+	db *bolt.DB
+}
+
+var (
+	setsBucket    = []byte("sets")
+	historyBucket = []byte("history")
+)
+
+// routeSet is one named, persisted group of routes.
+type routeSet struct {
+	Name      string      `json:"name"`
+	Routes    []routeJSON `json:"routes"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// historyEntry is one past hex rendering of a route set.
+type historyEntry struct {
+	Hex       string    `json:"hex"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// OpenStore opens (creating if necessary) the bbolt file at path and
+// ensures its top-level buckets exist.
+func OpenStore(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open route set store: %w", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(setsBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(historyBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error { return s.db.Close() }
+
+// Save creates or overwrites the named route set and appends its hex
+// rendering to the set's history.
+func (s *Store) Save(name string, routes []routeJSON) (routeSet, error) {
+	parsed, err := routeJSONsToRoutes(routes)
+	if err != nil {
+		return routeSet{}, err
+	}
+	data, err := hexnet.Encode(parsed)
+	if err != nil {
+		return routeSet{}, err
+	}
+	encodeTotal.Inc()
+
+	rs := routeSet{Name: name, Routes: routes, UpdatedAt: time.Now().UTC()}
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		encoded, err := json.Marshal(rs)
+		if err != nil {
+			return err
+		}
+		if err := tx.Bucket(setsBucket).Put([]byte(name), encoded); err != nil {
+			return err
+		}
+
+		nameBucket, err := tx.Bucket(historyBucket).CreateBucketIfNotExists([]byte(name))
+		if err != nil {
+			return err
+		}
+		entry, err := json.Marshal(historyEntry{Hex: "0x" + hex.EncodeToString(data), Timestamp: rs.UpdatedAt})
+		if err != nil {
+			return err
+		}
+		seq, err := nameBucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		return nameBucket.Put(seqKey(seq), entry)
+	})
+	if err != nil {
+		return routeSet{}, err
+	}
+	return rs, nil
+}
+
+// Get returns the named route set, or ok=false if it doesn't exist.
+func (s *Store) Get(name string) (rs routeSet, ok bool, err error) {
+	err = s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(setsBucket).Get([]byte(name))
+		if v == nil {
+			return nil
+		}
+		ok = true
+		return json.Unmarshal(v, &rs)
+	})
+	return rs, ok, err
+}
+
+// List returns every saved route set, ordered by name.
+func (s *Store) List() ([]routeSet, error) {
+	var out []routeSet
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(setsBucket).ForEach(func(_, v []byte) error {
+			var rs routeSet
+			if err := json.Unmarshal(v, &rs); err != nil {
+				return err
+			}
+			out = append(out, rs)
+			return nil
+		})
+	})
+	return out, err
+}
+
+// Delete removes the named route set and its history.
+func (s *Store) Delete(name string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(setsBucket).Delete([]byte(name)); err != nil {
+			return err
+		}
+		return tx.Bucket(historyBucket).DeleteBucket([]byte(name))
+	})
+}
+
+// History returns the named set's past hex renderings, oldest first. It
+// returns an empty slice (not an error) if the set has no history yet.
+func (s *Store) History(name string) ([]historyEntry, error) {
+	var out []historyEntry
+	err := s.db.View(func(tx *bolt.Tx) error {
+		nameBucket := tx.Bucket(historyBucket).Bucket([]byte(name))
+		if nameBucket == nil {
+			return nil
+		}
+		return nameBucket.ForEach(func(_, v []byte) error {
+			var entry historyEntry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			out = append(out, entry)
+			return nil
+		})
+	})
+	return out, err
+}
+
+// seqKey renders a bbolt auto-increment sequence as a fixed-width,
+// lexicographically-sortable key so ForEach walks history oldest first.
+func seqKey(seq uint64) []byte {
+	return []byte(fmt.Sprintf("%020d", seq))
+}
+
+func routeJSONsToRoutes(in []routeJSON) ([]hexnet.Route, error) {
+	routes := make([]hexnet.Route, 0, len(in))
+	for _, rj := range in {
+		_, ipNet, err := net.ParseCIDR(rj.Target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid target CIDR %q: %w", rj.Target, err)
+		}
+		nh := net.ParseIP(rj.NextHop)
+		if nh == nil {
+			return nil, fmt.Errorf("invalid route IP: %s", rj.NextHop)
+		}
+		routes = append(routes, hexnet.Route{Target: ipNet, NextHop: nh})
+	}
+	return routes, nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := OpenStore(filepath.Join(t.TempDir(), "hexnet_sets.db"))
+	if err != nil {
+		t.Fatalf("OpenStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestStoreSaveGetRoundTrip(t *testing.T) {
+	store := openTestStore(t)
+	routes := []routeJSON{{Target: "192.168.1.0/24", NextHop: "192.168.0.1"}}
+
+	saved, err := store.Save("office", routes)
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if saved.Name != "office" {
+		t.Errorf("Name = %q, want %q", saved.Name, "office")
+	}
+
+	got, ok, err := store.Get("office")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !ok {
+		t.Fatal("Get: set not found")
+	}
+	if len(got.Routes) != 1 || got.Routes[0] != routes[0] {
+		t.Errorf("Get routes = %+v, want %+v", got.Routes, routes)
+	}
+}
+
+func TestStoreGetMissingReturnsNotOK(t *testing.T) {
+	store := openTestStore(t)
+	_, ok, err := store.Get("does-not-exist")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if ok {
+		t.Error("Get: expected ok=false for a set that was never saved")
+	}
+}
+
+func TestStoreListReturnsEverySavedSet(t *testing.T) {
+	store := openTestStore(t)
+	routes := []routeJSON{{Target: "192.168.1.0/24", NextHop: "192.168.0.1"}}
+	if _, err := store.Save("a", routes); err != nil {
+		t.Fatalf("Save a: %v", err)
+	}
+	if _, err := store.Save("b", routes); err != nil {
+		t.Fatalf("Save b: %v", err)
+	}
+
+	sets, err := store.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(sets) != 2 {
+		t.Fatalf("List returned %d sets, want 2", len(sets))
+	}
+}
+
+func TestStoreHistoryAccumulatesAcrossSaves(t *testing.T) {
+	store := openTestStore(t)
+	routes := []routeJSON{{Target: "192.168.1.0/24", NextHop: "192.168.0.1"}}
+
+	if _, err := store.Save("office", routes); err != nil {
+		t.Fatalf("Save #1: %v", err)
+	}
+	routes2 := []routeJSON{{Target: "10.0.0.0/8", NextHop: "192.168.0.1"}}
+	if _, err := store.Save("office", routes2); err != nil {
+		t.Fatalf("Save #2: %v", err)
+	}
+
+	history, err := store.History("office")
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("History returned %d entries, want 2", len(history))
+	}
+	if history[0].Hex == history[1].Hex {
+		t.Error("expected the two history entries to differ after a changed save")
+	}
+}
+
+func TestStoreHistoryEmptyForUnknownSet(t *testing.T) {
+	store := openTestStore(t)
+	history, err := store.History("never-saved")
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(history) != 0 {
+		t.Errorf("History = %v, want empty", history)
+	}
+}
+
+func TestStoreDeleteRemovesSetAndHistory(t *testing.T) {
+	store := openTestStore(t)
+	routes := []routeJSON{{Target: "192.168.1.0/24", NextHop: "192.168.0.1"}}
+	if _, err := store.Save("office", routes); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if err := store.Delete("office"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if _, ok, err := store.Get("office"); err != nil || ok {
+		t.Errorf("Get after Delete: ok=%v, err=%v, want ok=false", ok, err)
+	}
+	if history, err := store.History("office"); err != nil || len(history) != 0 {
+		t.Errorf("History after Delete: %v, %v, want empty", history, err)
+	}
+}
@@ -0,0 +1,106 @@
+package hexnet
+
+import (
+	"fmt"
+	"net"
+)
+
+// Codec encodes and decodes routes to and from a wire representation. The
+// package's free Encode/Decode functions implement RFC 3442 classless static
+// routes; other on-wire framings (e.g. the IPv6 variant) can implement the
+// same interface.
+type Codec interface {
+	Encode(routes []Route) ([]byte, error)
+	Decode(data []byte) ([]Route, error)
+}
+
+// RFC3442Codec is the Codec backed by Encode and Decode.
+type RFC3442Codec struct{}
+
+func (RFC3442Codec) Encode(routes []Route) ([]byte, error) { return Encode(routes) }
+func (RFC3442Codec) Decode(data []byte) ([]Route, error)   { return Decode(data) }
+
+// Encode renders routes as the raw classless static route option payload.
+//
+// When every route is IPv4, the result is exactly the RFC 3442 wire format
+// real DHCP servers expect: for each route a prefix-length byte, the
+// ceil(prefix/8) significant octets of the target, and the 4-byte IPv4
+// router, back to back, with no extra framing.
+//
+// IPv6 has no room in that framing (there is nothing to tell a /32 IPv4
+// record apart from a /32 IPv6 one), so as soon as any route is IPv6, Encode
+// switches to the extended form documented on decode: see ipv6.go.
+func Encode(routes []Route) ([]byte, error) {
+	if allIPv4(routes) {
+		return encodeRFC3442(routes)
+	}
+	return encodeTagged(routes)
+}
+
+// Decode parses an option payload produced by Encode back into routes. It
+// recognizes the extended, mixed-family framing by its leading marker byte
+// (see ipv6.go) and otherwise falls back to plain RFC 3442 IPv4 decoding.
+func Decode(b []byte) ([]Route, error) {
+	if len(b) > 0 && b[0] == ipv6StreamMarker {
+		return decodeTagged(b[1:])
+	}
+	return decodeRFC3442(b)
+}
+
+func encodeRFC3442(routes []Route) ([]byte, error) {
+	var buf []byte
+	for _, rt := range routes {
+		if rt.Target == nil {
+			return nil, fmt.Errorf("route has no target")
+		}
+		ones, _ := rt.Target.Mask.Size()
+		nBytes := cidrPrefixBytes(ones)
+		targetRaw, err := ipToHexBytes(rt.Target.IP, nBytes)
+		if err != nil {
+			return nil, fmt.Errorf("target ip error: %w", err)
+		}
+
+		nh := rt.NextHop.To4()
+		if nh == nil {
+			return nil, fmt.Errorf("route ip error: only IPv4 supported: %v", rt.NextHop)
+		}
+
+		buf = append(buf, byte(ones))
+		buf = append(buf, targetRaw...)
+		buf = append(buf, nh...)
+	}
+	return buf, nil
+}
+
+func decodeRFC3442(b []byte) ([]Route, error) {
+	var routes []Route
+	i := 0
+	for i < len(b) {
+		prefixLen := int(b[i])
+		i++
+		if prefixLen > 32 {
+			return routes, fmt.Errorf("invalid prefix length /%d for IPv4 record", prefixLen)
+		}
+		nBytes := cidrPrefixBytes(prefixLen)
+		if i+nBytes+4 > len(b) {
+			return routes, fmt.Errorf("not enough data for record")
+		}
+
+		netPart := b[i : i+nBytes]
+		i += nBytes
+		routePart := b[i : i+4]
+		i += 4
+
+		netIP := make([]byte, 4)
+		copy(netIP, netPart)
+
+		routes = append(routes, Route{
+			Target: &net.IPNet{
+				IP:   net.IPv4(netIP[0], netIP[1], netIP[2], netIP[3]),
+				Mask: net.CIDRMask(prefixLen, 32),
+			},
+			NextHop: net.IPv4(routePart[0], routePart[1], routePart[2], routePart[3]),
+		})
+	}
+	return routes, nil
+}
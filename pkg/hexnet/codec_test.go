@@ -0,0 +1,167 @@
+package hexnet
+
+import (
+	"net"
+	"testing"
+)
+
+func mustRoute(t *testing.T, cidr, nexthop string) Route {
+	t.Helper()
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		t.Fatalf("ParseCIDR(%q): %v", cidr, err)
+	}
+	nh := net.ParseIP(nexthop)
+	if nh == nil {
+		t.Fatalf("ParseIP(%q) failed", nexthop)
+	}
+	return Route{Target: ipNet, NextHop: nh}
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes []Route
+		want   string // hex form, without 0x prefix
+	}{
+		{
+			name:   "single /24",
+			routes: []Route{mustRoute(t, "192.168.1.0/24", "192.168.0.1")},
+			want:   "18c0a801c0a80001",
+		},
+		{
+			name:   "default route",
+			routes: []Route{mustRoute(t, "0.0.0.0/0", "10.0.0.1")},
+			want:   "000a000001",
+		},
+		{
+			name:   "non-octet-aligned prefix",
+			routes: []Route{mustRoute(t, "192.168.1.0/23", "192.168.0.1")},
+			want:   "17c0a800c0a80001",
+		},
+		{
+			name: "multiple records",
+			routes: []Route{
+				mustRoute(t, "192.168.1.0/24", "192.168.0.1"),
+				mustRoute(t, "10.0.0.0/8", "10.0.0.1"),
+			},
+			want: "18c0a801c0a80001080a0a000001",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Encode(tt.routes)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			if got := hexString(data); got != tt.want {
+				t.Errorf("Encode = %s, want %s", got, tt.want)
+			}
+
+			decoded, err := Decode(data)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if len(decoded) != len(tt.routes) {
+				t.Fatalf("Decode returned %d routes, want %d", len(decoded), len(tt.routes))
+			}
+			for i, rt := range decoded {
+				wantOnes, _ := tt.routes[i].Target.Mask.Size()
+				gotOnes, _ := rt.Target.Mask.Size()
+				if gotOnes != wantOnes || !rt.Target.IP.Equal(tt.routes[i].Target.IP) {
+					t.Errorf("route %d target = %v/%d, want %v/%d", i, rt.Target.IP, gotOnes, tt.routes[i].Target.IP, wantOnes)
+				}
+				if !rt.NextHop.Equal(tt.routes[i].NextHop) {
+					t.Errorf("route %d nexthop = %v, want %v", i, rt.NextHop, tt.routes[i].NextHop)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeTruncated(t *testing.T) {
+	if _, err := Decode([]byte{24, 192, 168}); err == nil {
+		t.Fatal("expected error decoding truncated record, got nil")
+	}
+}
+
+func TestDecodeRejectsOversizedPrefix(t *testing.T) {
+	if _, err := Decode([]byte{160, 0, 0, 0, 0, 0, 0, 0}); err == nil {
+		t.Fatal("expected error decoding record with prefix length > 32, got nil")
+	}
+}
+
+func TestRFC3442CodecMatchesFreeFunctions(t *testing.T) {
+	routes := []Route{mustRoute(t, "192.168.1.0/24", "192.168.0.1")}
+	var c Codec = RFC3442Codec{}
+
+	want, err := Encode(routes)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	got, err := c.Encode(routes)
+	if err != nil {
+		t.Fatalf("Codec.Encode: %v", err)
+	}
+	if hexString(got) != hexString(want) {
+		t.Errorf("Codec.Encode = %s, want %s", hexString(got), hexString(want))
+	}
+}
+
+func FuzzDecode(f *testing.F) {
+	f.Add([]byte{24, 192, 168, 1, 192, 168, 0, 1})
+	f.Add([]byte{})
+	f.Add([]byte{32})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Decode must never panic on arbitrary input; a malformed trailing
+		// record is reported as an error, not a crash.
+		routes, err := Decode(data)
+		if err != nil {
+			return
+		}
+		reEncoded, err := Encode(routes)
+		if err != nil {
+			t.Fatalf("Encode of successfully decoded routes failed: %v", err)
+		}
+		// Encode always prefers the minimal untagged RFC 3442 form when every
+		// route is IPv4, even if data happened to use the tagged framing for
+		// an all-IPv4 payload, so re-encoding isn't guaranteed byte-for-byte
+		// identical to data. What must hold is that decoding it again yields
+		// the same routes.
+		reDecoded, err := Decode(reEncoded)
+		if err != nil {
+			t.Fatalf("Decode of re-encoded data failed: %v", err)
+		}
+		if !routesEqual(reDecoded, routes) {
+			t.Errorf("round trip changed routes: got %+v, want %+v", reDecoded, routes)
+		}
+	})
+}
+
+func routesEqual(a, b []Route) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		aOnes, aBits := a[i].Target.Mask.Size()
+		bOnes, bBits := b[i].Target.Mask.Size()
+		if aOnes != bOnes || aBits != bBits || !a[i].Target.IP.Equal(b[i].Target.IP) {
+			return false
+		}
+		if !a[i].NextHop.Equal(b[i].NextHop) {
+			return false
+		}
+	}
+	return true
+}
+
+func hexString(b []byte) string {
+	const hexDigits = "0123456789abcdef"
+	out := make([]byte, len(b)*2)
+	for i, v := range b {
+		out[i*2] = hexDigits[v>>4]
+		out[i*2+1] = hexDigits[v&0xf]
+	}
+	return string(out)
+}
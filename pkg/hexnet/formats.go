@@ -0,0 +1,257 @@
+package hexnet
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// --- Per-format renderers/parsers for the classless static route option ---
+//
+// The same RFC 3442 payload (Encode/Decode) is carried over the wire
+// differently by different DHCP tooling. These helpers convert between that
+// payload and the textual forms ISC dhcpd, dnsmasq and MikroTik RouterOS
+// actually expect.
+
+// errNoIPv6Rendering reports that format has no wire representation for this
+// package's own IPv6 tagged framing (see ipv6.go): real dhcpd/dnsmasq/
+// MikroTik installs only ever see RFC 3442's plain IPv4 option 121 payload.
+func errNoIPv6Rendering(format string) error {
+	return fmt.Errorf("%s has no representation for IPv6/mixed-family classless static routes; use the hex encoding instead", format)
+}
+
+// RenderISCDHCPD renders the option payload as an ISC dhcpd config line,
+// e.g. `option classless-static-routes = 24, 192, 168, 1, 192, 168, 0, 1;`.
+// It errors on a tagged (IPv6 or mixed-family) payload: dhcpd only knows the
+// plain RFC 3442 bytes.
+func RenderISCDHCPD(data []byte) (string, error) {
+	if len(data) > 0 && data[0] == ipv6StreamMarker {
+		return "", errNoIPv6Rendering("ISC dhcpd classless-static-routes")
+	}
+	parts := make([]string, len(data))
+	for i, b := range data {
+		parts[i] = strconv.Itoa(int(b))
+	}
+	return "option classless-static-routes = " + strings.Join(parts, ", ") + ";", nil
+}
+
+// ParseISCDHCPD parses an ISC dhcpd `option classless-static-routes = ...;`
+// line back into the raw option payload.
+func ParseISCDHCPD(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, "="); i >= 0 {
+		s = s[i+1:]
+	}
+	s = strings.TrimSuffix(strings.TrimSpace(s), ";")
+	fields := strings.Split(s, ",")
+	buf := make([]byte, 0, len(fields))
+	for _, f := range fields {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 || n > 255 {
+			return nil, fmt.Errorf("invalid byte %q in classless-static-routes option", f)
+		}
+		buf = append(buf, byte(n))
+	}
+	return buf, nil
+}
+
+// RenderDnsmasq renders routes as dnsmasq `--dhcp-option=121,...` lines, one
+// per route, using dnsmasq's native CIDR/next-hop syntax. It errors if any
+// route is IPv6: dnsmasq's option 121 is IPv4-only.
+func RenderDnsmasq(routes []Route) (string, error) {
+	lines := make([]string, len(routes))
+	for i, rt := range routes {
+		if routeFamily(rt) != familyIPv4 {
+			return "", errNoIPv6Rendering("dnsmasq dhcp-option=121")
+		}
+		ones, _ := rt.Target.Mask.Size()
+		lines[i] = fmt.Sprintf("--dhcp-option=121,%s/%d,%s", rt.Target.IP.String(), ones, rt.NextHop.String())
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// ParseDnsmasq parses one or more dnsmasq `--dhcp-option=121,cidr,nexthop`
+// lines into routes.
+func ParseDnsmasq(s string) ([]Route, error) {
+	var routes []Route
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		line = strings.TrimPrefix(line, "--dhcp-option=")
+		fields := strings.Split(line, ",")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid dnsmasq dhcp-option line: %q", line)
+		}
+		if strings.TrimSpace(fields[0]) != "121" {
+			return nil, fmt.Errorf("not a classless-static-routes (121) option: %q", line)
+		}
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(fields[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid target CIDR: %w", err)
+		}
+		nh := net.ParseIP(strings.TrimSpace(fields[2]))
+		if nh == nil {
+			return nil, fmt.Errorf("invalid route IP: %s", fields[2])
+		}
+		routes = append(routes, Route{Target: ipNet, NextHop: nh})
+	}
+	return routes, nil
+}
+
+// RenderMikroTik renders the option payload as a RouterOS script block that
+// adds it as a DHCP server option. It errors on a tagged (IPv6 or
+// mixed-family) payload: RouterOS expects plain RFC 3442 bytes for code 121.
+func RenderMikroTik(data []byte) (string, error) {
+	if len(data) > 0 && data[0] == ipv6StreamMarker {
+		return "", errNoIPv6Rendering("MikroTik option 121")
+	}
+	return fmt.Sprintf("/ip dhcp-server option\nadd name=classless-static-routes code=121 value=0x%s\n", hex.EncodeToString(data)), nil
+}
+
+// ParseMikroTik extracts the option payload out of a RouterOS
+// `/ip dhcp-server option add ... value=0x...` script block.
+func ParseMikroTik(s string) ([]byte, error) {
+	idx := strings.Index(s, "value=")
+	if idx < 0 {
+		return nil, fmt.Errorf("no value=0x... found in MikroTik script")
+	}
+	rest := s[idx+len("value="):]
+	fields := strings.FieldsFunc(rest, func(r rune) bool {
+		return r == ' ' || r == '\n' || r == '\t' || r == '\r'
+	})
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no value=0x... found in MikroTik script")
+	}
+	hexStr := strings.TrimPrefix(fields[0], "0x")
+	return hex.DecodeString(hexStr)
+}
+
+// Renderings holds every supported textual encoding of one classless static
+// route option payload, used by the "Copy as" UI and /api/convert.
+type Renderings struct {
+	Hex      string `json:"hex"`
+	Dhcpd    string `json:"dhcpd"`
+	Dnsmasq  string `json:"dnsmasq"`
+	MikroTik string `json:"mikrotik"`
+}
+
+// RenderAll renders routes in every supported textual format. The hex
+// encoding always succeeds; the other formats have no room for this
+// package's own IPv6 tagged framing (see ipv6.go), so for an IPv6 or
+// mixed-family route set their fields hold an explanatory message instead of
+// failing the whole call, mirroring how per-line errors are reported
+// elsewhere in this package (e.g. Pair.Error).
+func RenderAll(routes []Route) (Renderings, error) {
+	data, err := Encode(routes)
+	if err != nil {
+		return Renderings{}, err
+	}
+	rend := Renderings{Hex: "0x" + hex.EncodeToString(data)}
+
+	if dhcpd, err := RenderISCDHCPD(data); err != nil {
+		rend.Dhcpd = err.Error()
+	} else {
+		rend.Dhcpd = dhcpd
+	}
+	if dnsmasq, err := RenderDnsmasq(routes); err != nil {
+		rend.Dnsmasq = err.Error()
+	} else {
+		rend.Dnsmasq = dnsmasq
+	}
+	if mikrotik, err := RenderMikroTik(data); err != nil {
+		rend.MikroTik = err.Error()
+	} else {
+		rend.MikroTik = mikrotik
+	}
+	return rend, nil
+}
+
+// Supported /api/convert and "paste" input formats.
+const (
+	FormatAuto     = "auto"
+	FormatCIDR     = "cidr"
+	FormatHex      = "hex"
+	FormatDHCPD    = "dhcpd"
+	FormatDnsmasq  = "dnsmasq"
+	FormatMikroTik = "mikrotik"
+)
+
+// DetectFormat guesses the format of pasted/uploaded text so callers can
+// accept any of the formats the package also emits.
+func DetectFormat(input string) string {
+	trimmed := strings.TrimSpace(input)
+	switch {
+	case strings.Contains(trimmed, "classless-static-routes ="):
+		return FormatDHCPD
+	case strings.HasPrefix(trimmed, "--dhcp-option="):
+		return FormatDnsmasq
+	case strings.Contains(trimmed, "/ip dhcp-server option"):
+		return FormatMikroTik
+	case strings.HasPrefix(trimmed, "0x") || strings.HasPrefix(trimmed, "0X"):
+		return FormatHex
+	default:
+		return FormatCIDR
+	}
+}
+
+// ParseInput parses input in the given format (or auto-detects it, when
+// format is "" or FormatAuto) into routes.
+func ParseInput(format, input string) ([]Route, error) {
+	if format == "" || format == FormatAuto {
+		format = DetectFormat(input)
+	}
+	switch format {
+	case FormatHex:
+		data, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(input), "0x"), "0X"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex string: %w", err)
+		}
+		return Decode(data)
+	case FormatDHCPD:
+		data, err := ParseISCDHCPD(input)
+		if err != nil {
+			return nil, err
+		}
+		return Decode(data)
+	case FormatDnsmasq:
+		return ParseDnsmasq(input)
+	case FormatMikroTik:
+		data, err := ParseMikroTik(input)
+		if err != nil {
+			return nil, err
+		}
+		return Decode(data)
+	case FormatCIDR:
+		var routes []Route
+		for _, line := range strings.Split(strings.TrimSpace(input), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			parts := strings.Fields(line)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("line format invalid: %s", line)
+			}
+			_, ipNet, err := net.ParseCIDR(parts[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid target CIDR: %w", err)
+			}
+			nh := net.ParseIP(parts[1])
+			if nh == nil {
+				return nil, fmt.Errorf("invalid route IP: %s", parts[1])
+			}
+			routes = append(routes, Route{Target: ipNet, NextHop: nh})
+		}
+		return routes, nil
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
@@ -0,0 +1,34 @@
+package hexnet
+
+import "testing"
+
+func TestRenderAllRejectsIPv6ForProtocolSpecificFormats(t *testing.T) {
+	routes := []Route{mustRoute(t, "2001:db8::/32", "fe80::1")}
+	rend, err := RenderAll(routes)
+	if err != nil {
+		t.Fatalf("RenderAll: %v", err)
+	}
+	if rend.Hex == "" {
+		t.Error("Hex rendering should still succeed for IPv6 routes")
+	}
+	for name, got := range map[string]string{
+		"Dhcpd":    rend.Dhcpd,
+		"Dnsmasq":  rend.Dnsmasq,
+		"MikroTik": rend.MikroTik,
+	} {
+		if got == "" {
+			t.Errorf("%s rendering is empty, want an explanatory message", name)
+		}
+	}
+}
+
+func TestRenderAllPureIPv4StillRendersEveryFormat(t *testing.T) {
+	routes := []Route{mustRoute(t, "192.168.1.0/24", "192.168.0.1")}
+	rend, err := RenderAll(routes)
+	if err != nil {
+		t.Fatalf("RenderAll: %v", err)
+	}
+	if rend.Dhcpd == "" || rend.Dnsmasq == "" || rend.MikroTik == "" {
+		t.Errorf("expected every format populated for pure IPv4 routes, got %+v", rend)
+	}
+}
@@ -0,0 +1,142 @@
+package hexnet
+
+import (
+	"fmt"
+	"net"
+)
+
+// RFC 3442 has no address-family field: a record is just a prefix-length
+// byte, its significant octets, and a fixed 4-byte IPv4 router, so there is
+// no way to tell a /32 IPv4 record from a /32 IPv6 one by inspection alone.
+// To let IPv6 classless static routes share a stream with RFC 3442 data
+// (mirroring how implementations add an equivalent option for DHCPv6), any
+// payload containing an IPv6 route is prefixed with a single marker byte,
+// followed by one tagged record per route:
+//
+//	ipv6StreamMarker (1 byte, 0xFF)
+//	per record: family (1 byte: 4 or 6), prefix length (1 byte),
+//	            ceil(prefix/8) significant target octets,
+//	            router (4 bytes for family 4, 16 bytes for family 6)
+//
+// Payloads with no marker byte are assumed to be plain RFC 3442 (IPv4-only),
+// so pure-IPv4 output from Encode is always byte-for-byte what a real DHCP
+// server expects.
+const ipv6StreamMarker = 0xFF
+
+const (
+	familyIPv4 = 4
+	familyIPv6 = 6
+)
+
+func routeFamily(rt Route) int {
+	if rt.Target != nil && len(rt.Target.Mask) == net.IPv4len {
+		return familyIPv4
+	}
+	return familyIPv6
+}
+
+func allIPv4(routes []Route) bool {
+	for _, rt := range routes {
+		if routeFamily(rt) != familyIPv4 {
+			return false
+		}
+	}
+	return true
+}
+
+func encodeTagged(routes []Route) ([]byte, error) {
+	buf := []byte{ipv6StreamMarker}
+	for _, rt := range routes {
+		if rt.Target == nil {
+			return nil, fmt.Errorf("route has no target")
+		}
+		family := routeFamily(rt)
+		ones, _ := rt.Target.Mask.Size()
+		nBytes := cidrPrefixBytes(ones)
+
+		var targetRaw, nh []byte
+		if family == familyIPv4 {
+			v4 := rt.Target.IP.To4()
+			if v4 == nil || nBytes > net.IPv4len {
+				return nil, fmt.Errorf("target ip error: invalid IPv4 prefix /%d", ones)
+			}
+			targetRaw = v4[:nBytes]
+			nh = rt.NextHop.To4()
+			if nh == nil {
+				return nil, fmt.Errorf("route ip error: family mismatch, next hop %v is not IPv4", rt.NextHop)
+			}
+		} else {
+			v6 := rt.Target.IP.To16()
+			if v6 == nil || rt.Target.IP.To4() != nil || nBytes > net.IPv6len {
+				return nil, fmt.Errorf("target ip error: invalid IPv6 prefix /%d", ones)
+			}
+			targetRaw = v6[:nBytes]
+			nh = rt.NextHop.To16()
+			if nh == nil || rt.NextHop.To4() != nil {
+				return nil, fmt.Errorf("route ip error: family mismatch, next hop %v is not IPv6", rt.NextHop)
+			}
+		}
+
+		buf = append(buf, byte(family), byte(ones))
+		buf = append(buf, targetRaw...)
+		buf = append(buf, nh...)
+	}
+	return buf, nil
+}
+
+func decodeTagged(b []byte) ([]Route, error) {
+	if len(b) == 0 {
+		return nil, fmt.Errorf("truncated record header: marker byte present with no records")
+	}
+	var routes []Route
+	i := 0
+	for i < len(b) {
+		if i+2 > len(b) {
+			return routes, fmt.Errorf("truncated record header")
+		}
+		family := int(b[i])
+		prefixLen := int(b[i+1])
+		i += 2
+
+		var bits, nhLen int
+		switch family {
+		case familyIPv4:
+			bits, nhLen = 32, net.IPv4len
+		case familyIPv6:
+			bits, nhLen = 128, net.IPv6len
+		default:
+			return routes, fmt.Errorf("invalid address family byte: %d", family)
+		}
+		if prefixLen > bits {
+			return routes, fmt.Errorf("invalid prefix length /%d for family %d", prefixLen, family)
+		}
+
+		nBytes := cidrPrefixBytes(prefixLen)
+		if i+nBytes+nhLen > len(b) {
+			return routes, fmt.Errorf("not enough data for record")
+		}
+
+		targetPart := b[i : i+nBytes]
+		i += nBytes
+		nhPart := b[i : i+nhLen]
+		i += nhLen
+
+		targetIP := make([]byte, bits/8)
+		copy(targetIP, targetPart)
+		nh := make([]byte, nhLen)
+		copy(nh, nhPart)
+
+		var ip net.IP
+		if family == familyIPv4 {
+			ip = net.IPv4(targetIP[0], targetIP[1], targetIP[2], targetIP[3])
+		} else {
+			ip = net.IP(targetIP)
+		}
+
+		routes = append(routes, Route{
+			Target:  &net.IPNet{IP: ip, Mask: net.CIDRMask(prefixLen, bits)},
+			NextHop: net.IP(nh),
+		})
+	}
+	return routes, nil
+}
@@ -0,0 +1,106 @@
+package hexnet
+
+import (
+	"net"
+	"testing"
+)
+
+func TestEncodeDecodeIPv6(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes []Route
+	}{
+		{
+			name:   "single IPv6 route",
+			routes: []Route{mustRoute(t, "2001:db8::/32", "fe80::1")},
+		},
+		{
+			name: "mixed IPv4 and IPv6",
+			routes: []Route{
+				mustRoute(t, "192.168.1.0/24", "192.168.0.1"),
+				mustRoute(t, "2001:db8::/32", "fe80::1"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Encode(tt.routes)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			if data[0] != ipv6StreamMarker {
+				t.Fatalf("expected tagged stream marker 0x%x as first byte, got 0x%x", ipv6StreamMarker, data[0])
+			}
+
+			decoded, err := Decode(data)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if len(decoded) != len(tt.routes) {
+				t.Fatalf("Decode returned %d routes, want %d", len(decoded), len(tt.routes))
+			}
+			for i, rt := range decoded {
+				wantOnes, _ := tt.routes[i].Target.Mask.Size()
+				gotOnes, _ := rt.Target.Mask.Size()
+				if gotOnes != wantOnes || !rt.Target.IP.Equal(tt.routes[i].Target.IP) {
+					t.Errorf("route %d target = %v/%d, want %v/%d", i, rt.Target.IP, gotOnes, tt.routes[i].Target.IP, wantOnes)
+				}
+				if !rt.NextHop.Equal(tt.routes[i].NextHop) {
+					t.Errorf("route %d nexthop = %v, want %v", i, rt.NextHop, tt.routes[i].NextHop)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodePureIPv4StaysUntaggedRFC3442(t *testing.T) {
+	routes := []Route{mustRoute(t, "192.168.1.0/24", "192.168.0.1")}
+	data, err := Encode(routes)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if hexString(data) != "18c0a801c0a80001" {
+		t.Errorf("pure IPv4 Encode changed wire format: got %s", hexString(data))
+	}
+}
+
+func TestDecodeTaggedRejectsBadFamily(t *testing.T) {
+	_, err := Decode([]byte{ipv6StreamMarker, 9, 24})
+	if err == nil {
+		t.Fatal("expected error decoding record with invalid family byte, got nil")
+	}
+}
+
+func TestDecodeTaggedRejectsMarkerWithNoRecords(t *testing.T) {
+	_, err := Decode([]byte{ipv6StreamMarker})
+	if err == nil {
+		t.Fatal("expected error decoding lone marker byte with no records, got nil")
+	}
+}
+
+func FuzzDecodeTagged(f *testing.F) {
+	_, ipNet, _ := net.ParseCIDR("2001:db8::/32")
+	data, _ := Encode([]Route{{Target: ipNet, NextHop: net.ParseIP("fe80::1")}})
+	f.Add(data)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		routes, err := Decode(data)
+		if err != nil {
+			return
+		}
+		reEncoded, err := Encode(routes)
+		if err != nil {
+			t.Fatalf("Encode of successfully decoded routes failed: %v", err)
+		}
+		// See FuzzDecode in codec_test.go: re-encoding an all-IPv4 tagged
+		// payload switches to the minimal untagged form, so only route-level
+		// equivalence is guaranteed, not identical bytes.
+		reDecoded, err := Decode(reEncoded)
+		if err != nil {
+			t.Fatalf("Decode of re-encoded data failed: %v", err)
+		}
+		if !routesEqual(reDecoded, routes) {
+			t.Errorf("round trip changed routes: got %+v, want %+v", reDecoded, routes)
+		}
+	})
+}
@@ -0,0 +1,36 @@
+// Package hexnet implements encoding and decoding of the DHCP classless
+// static route option (RFC 3442, DHCP options 121 and 249) and converts it
+// to and from the textual forms used by ISC dhcpd, dnsmasq and MikroTik
+// RouterOS.
+package hexnet
+
+import (
+	"fmt"
+	"math"
+	"net"
+)
+
+// Route is a single classless static route: a destination prefix and the
+// router (next hop) that traffic to it should be sent through.
+type Route struct {
+	Target  *net.IPNet
+	NextHop net.IP
+}
+
+func ipToHexBytes(ip net.IP, bytes int) ([]byte, error) {
+	v4 := ip.To4()
+	if v4 == nil {
+		return nil, fmt.Errorf("only IPv4 supported: %v", ip)
+	}
+	if bytes < 0 || bytes > 4 {
+		return nil, fmt.Errorf("invalid bytes: %d", bytes)
+	}
+	return v4[:bytes], nil
+}
+
+func cidrPrefixBytes(prefix int) int {
+	if prefix <= 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(prefix) / 8.0))
+}
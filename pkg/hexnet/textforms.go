@@ -0,0 +1,77 @@
+package hexnet
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Pair is one target/route row, rendered for display alongside its
+// single-record hex form and any parse error.
+type Pair struct {
+	Target string
+	Route  string
+	Hex    string
+	Error  string
+}
+
+// BuildHexString encodes a single targetCIDR/routeIP pair into its "0x..."
+// RFC 3442 hex form.
+func BuildHexString(targetCIDR string, routeIP string) (string, error) {
+	_, ipNet, err := net.ParseCIDR(targetCIDR)
+	if err != nil {
+		return "", fmt.Errorf("invalid target CIDR: %w", err)
+	}
+	r := net.ParseIP(routeIP)
+	if r == nil {
+		return "", fmt.Errorf("invalid route IP: %s", routeIP)
+	}
+
+	data, err := Encode([]Route{{Target: ipNet, NextHop: r}})
+	if err != nil {
+		return "", err
+	}
+	return "0x" + hex.EncodeToString(data), nil
+}
+
+// ParseHexStream decodes a "0x..." hex stream that may contain multiple
+// back-to-back RFC 3442 records into display rows.
+func ParseHexStream(hexStr string) ([]Pair, error) {
+	hexStr = strings.TrimSpace(hexStr)
+	if strings.HasPrefix(hexStr, "0x") || strings.HasPrefix(hexStr, "0X") {
+		hexStr = hexStr[2:]
+	}
+	data, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex string: %w", err)
+	}
+
+	routes, err := Decode(data)
+	results := RoutesToPairs(routes)
+	if err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// RoutesToPairs renders each route back to its own single-record hex string,
+// matching the historical one-record-per-row display of the UI.
+func RoutesToPairs(routes []Route) []Pair {
+	results := make([]Pair, 0, len(routes))
+	for _, rt := range routes {
+		ones, _ := rt.Target.Mask.Size()
+		recHex, err := Encode([]Route{rt})
+		p := Pair{
+			Target: fmt.Sprintf("%s/%d", rt.Target.IP.String(), ones),
+			Route:  rt.NextHop.String(),
+		}
+		if err != nil {
+			p.Error = err.Error()
+		} else {
+			p.Hex = "0x" + hex.EncodeToString(recHex)
+		}
+		results = append(results, p)
+	}
+	return results
+}